@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+// defaultSeenCap is used when SeenSet.Cap is zero.
+const defaultSeenCap = 512
+
+// SeenSet is a bounded, insertion-ordered set of item dedup keys, used to
+// recognize items the bot has already dispatched for a feed across runs.
+// Once more than Cap keys have been added, the oldest ones are evicted.
+// Keys is exported so it round-trips through the feed's YAML state.
+type SeenSet struct {
+	Cap  int      `yaml:"cap,omitempty"`
+	Keys []string `yaml:"keys,omitempty"`
+
+	mu    sync.Mutex
+	index map[string]struct{}
+}
+
+func (s *SeenSet) ensureIndex() {
+	if s.index != nil {
+		return
+	}
+	s.index = make(map[string]struct{}, len(s.Keys))
+	for _, k := range s.Keys {
+		s.index[k] = struct{}{}
+	}
+}
+
+// Seen reports whether key has already been recorded.
+func (s *SeenSet) Seen(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensureIndex()
+	_, ok := s.index[key]
+	return ok
+}
+
+// Add records key, evicting the oldest recorded key once Cap is exceeded.
+func (s *SeenSet) Add(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ensureIndex()
+	if _, ok := s.index[key]; ok {
+		return
+	}
+
+	cap := s.Cap
+	if cap <= 0 {
+		cap = defaultSeenCap
+	}
+
+	s.Keys = append(s.Keys, key)
+	s.index[key] = struct{}{}
+	for len(s.Keys) > cap {
+		oldest := s.Keys[0]
+		s.Keys = s.Keys[1:]
+		delete(s.index, oldest)
+	}
+}
+
+// ItemKey derives a stable dedup key for a feed item: its GUID (falling
+// back to the Atom/JSON Feed link when a feed has no GUID) plus a hash of
+// the normalized title and content, so an item republished with edits
+// still surfaces once rather than being lost to a missing or duplicated
+// timestamp.
+func ItemKey(item *ExtractedItem, rawContent string) string {
+	id := item.GUID
+	if id == "" {
+		id = item.Link
+	}
+	return id + "#" + contentHash(item.Title, rawContent)
+}
+
+func contentHash(title, content string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(title+" "+content)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,47 @@
+package feed
+
+import "testing"
+
+func TestSeenSetAddAndSeen(t *testing.T) {
+	var s SeenSet
+	key := ItemKey(&ExtractedItem{GUID: "guid-1", Title: "Hello"}, "body")
+
+	if s.Seen(key) {
+		t.Fatal("key should not be seen before Add")
+	}
+	s.Add(key)
+	if !s.Seen(key) {
+		t.Fatal("key should be seen after Add")
+	}
+}
+
+func TestSeenSetEvictsOldestOverCap(t *testing.T) {
+	s := SeenSet{Cap: 2}
+	s.Add("a")
+	s.Add("b")
+	s.Add("c")
+
+	if s.Seen("a") {
+		t.Fatal("oldest key should have been evicted")
+	}
+	if !s.Seen("b") || !s.Seen("c") {
+		t.Fatal("most recent keys should still be present")
+	}
+}
+
+func TestItemKeyFallsBackToLinkWithoutGUID(t *testing.T) {
+	item := &ExtractedItem{Link: "https://example.com/a"}
+	key := ItemKey(item, "content")
+	if key == "" || key[:len(item.Link)] != item.Link {
+		t.Fatalf("expected key to start with link, got %q", key)
+	}
+}
+
+func TestItemKeyChangesWithContent(t *testing.T) {
+	item := &ExtractedItem{GUID: "guid-1", Title: "Hello"}
+	k1 := ItemKey(item, "first version")
+	k2 := ItemKey(item, "edited version")
+	if k1 == k2 {
+		t.Fatal("expected key to change when content changes")
+	}
+}
@@ -0,0 +1,258 @@
+// Package feed normalizes gofeed items (RSS 2.0, Atom and JSON Feed) into a
+// single ExtractedItem shape so the rest of the bot never has to know which
+// dialect a given feed was published in.
+package feed
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// Image is a single piece of feed-supplied artwork, e.g. a Media RSS
+// thumbnail, an Atom/JSON Feed enclosure or a scraped <img>.
+type Image struct {
+	URL    string
+	Width  int
+	Height int
+}
+
+// area is used to rank images by resolution. Images with unknown dimensions
+// sort last.
+func (i *Image) area() int {
+	if i == nil {
+		return 0
+	}
+	return i.Width * i.Height
+}
+
+// Enclosure is a file attached to an item, e.g. audio, video or image.
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// ExtractedItem is a source-agnostic view of a single feed entry: title,
+// HTML description, plain summary, the best available image/thumbnail,
+// author and enclosures, normalized across RSS 2.0, Atom and JSON Feed.
+type ExtractedItem struct {
+	Title           string
+	Link            string
+	GUID            string
+	Author          string
+	DescriptionHTML string
+	Summary         string
+	Image           *Image
+	Thumbnail       *Image
+	Enclosures      []Enclosure
+}
+
+// Extract builds an ExtractedItem out of a raw gofeed item, preferring
+// publisher-supplied images (Media RSS, Atom/JSON Feed enclosures) over an
+// <img> scraped from the description, and picking the highest resolution
+// candidate when more than one is available.
+func Extract(item *gofeed.Item) *ExtractedItem {
+	if item == nil {
+		return nil
+	}
+
+	mediaDesc := mediaDescription(item.Extensions)
+	e := &ExtractedItem{
+		Title:           item.Title,
+		Link:            item.Link,
+		GUID:            item.GUID,
+		DescriptionHTML: firstNonEmpty(item.Description, item.Content, mediaDesc),
+		Summary:         plainText(firstNonEmpty(item.Description, item.Content, mediaDesc)),
+		Author:          itemAuthor(item),
+	}
+	if e.GUID == "" {
+		e.GUID = item.Link
+	}
+
+	e.Enclosures = extractEnclosures(item)
+
+	var candidates []*Image
+	if item.Image != nil && item.Image.URL != "" {
+		candidates = append(candidates, &Image{URL: item.Image.URL})
+	}
+	candidates = append(candidates, mediaImages(item.Extensions)...)
+	for _, enc := range e.Enclosures {
+		if strings.HasPrefix(enc.Type, "image/") {
+			candidates = append(candidates, &Image{URL: enc.URL})
+		}
+	}
+
+	if best := bestImage(candidates); best != nil {
+		e.Thumbnail = best
+	}
+	if scraped := scrapeFirstImage(item.Description); scraped != "" {
+		e.Image = &Image{URL: scraped}
+	}
+	// A publisher-supplied image always outranks one we scraped ourselves.
+	if e.Thumbnail != nil {
+		e.Image = e.Thumbnail
+	}
+
+	return e
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// plainText returns the text content of an HTML fragment with all markup
+// removed.
+func plainText(htmlStr string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlStr))
+	if err != nil {
+		return htmlStr
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
+func itemAuthor(item *gofeed.Item) string {
+	if item.Author != nil && item.Author.Name != "" {
+		return item.Author.Name
+	}
+	if len(item.Authors) > 0 && item.Authors[0].Name != "" {
+		return item.Authors[0].Name
+	}
+	return mediaCredit(item.Extensions)
+}
+
+func extractEnclosures(item *gofeed.Item) []Enclosure {
+	var out []Enclosure
+	for _, enc := range item.Enclosures {
+		if enc.URL == "" {
+			continue
+		}
+		length, _ := strconv.ParseInt(enc.Length, 10, 64)
+		out = append(out, Enclosure{URL: enc.URL, Type: enc.Type, Length: length})
+	}
+	return out
+}
+
+// mediaImages walks the Media RSS namespace (media:group, media:content,
+// media:thumbnail) for entries with a url, skipping those whose medium (or,
+// without a medium, whose MIME type) says they are not an image.
+func mediaImages(exts ext.Extensions) []*Image {
+	media, ok := exts["media"]
+	if !ok {
+		return nil
+	}
+
+	var out []*Image
+	collect := func(els []ext.Extension) {
+		for _, el := range els {
+			if img := imageFromExtension(el); img != nil {
+				out = append(out, img)
+			}
+		}
+	}
+	collect(media["thumbnail"])
+	collect(media["content"])
+	for _, group := range media["group"] {
+		collect(group.Children["thumbnail"])
+		collect(group.Children["content"])
+	}
+	return out
+}
+
+// mediaDescription returns the first media:description found directly on
+// the item or inside a media:group, for feeds that carry their body there
+// instead of in <description>/<content:encoded>.
+func mediaDescription(exts ext.Extensions) string {
+	media, ok := exts["media"]
+	if !ok {
+		return ""
+	}
+	if descs, ok := media["description"]; ok && len(descs) > 0 {
+		return descs[0].Value
+	}
+	for _, group := range media["group"] {
+		if descs, ok := group.Children["description"]; ok && len(descs) > 0 {
+			return descs[0].Value
+		}
+	}
+	return ""
+}
+
+// mediaCredit returns the first media:credit found directly on the item or
+// inside a media:group, used as a fallback author when a feed only
+// attributes content via Media RSS.
+func mediaCredit(exts ext.Extensions) string {
+	media, ok := exts["media"]
+	if !ok {
+		return ""
+	}
+	if credits, ok := media["credit"]; ok && len(credits) > 0 {
+		return credits[0].Value
+	}
+	for _, group := range media["group"] {
+		if credits, ok := group.Children["credit"]; ok && len(credits) > 0 {
+			return credits[0].Value
+		}
+	}
+	return ""
+}
+
+func imageFromExtension(el ext.Extension) *Image {
+	url := el.Attrs["url"]
+	if url == "" {
+		return nil
+	}
+	medium := el.Attrs["medium"]
+	mimeType := el.Attrs["type"]
+	if medium != "" && medium != "image" {
+		return nil
+	}
+	if medium == "" && mimeType != "" && !strings.HasPrefix(mimeType, "image/") {
+		return nil
+	}
+	width, _ := strconv.Atoi(el.Attrs["width"])
+	height, _ := strconv.Atoi(el.Attrs["height"])
+	return &Image{URL: url, Width: width, Height: height}
+}
+
+func bestImage(candidates []*Image) *Image {
+	var best *Image
+	for _, c := range candidates {
+		if c == nil || c.URL == "" {
+			continue
+		}
+		if best == nil || c.area() > best.area() {
+			best = c
+		}
+	}
+	return best
+}
+
+// scrapeFirstImage pulls the src of the first <img> out of an HTML
+// description, used as a last resort when a feed doesn't supply any
+// structured image of its own.
+func scrapeFirstImage(html string) string {
+	if html == "" {
+		return ""
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	var src string
+	doc.Find("img").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if v, exists := s.Attr("src"); exists {
+			src = v
+		}
+		return false
+	})
+	return src
+}
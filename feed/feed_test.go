@@ -0,0 +1,99 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+func TestExtractDescriptionFallback(t *testing.T) {
+	cases := []struct {
+		name string
+		item *gofeed.Item
+		want string
+	}{
+		{
+			name: "description only",
+			item: &gofeed.Item{Description: "<p>desc</p>"},
+			want: "<p>desc</p>",
+		},
+		{
+			name: "content:encoded with no description",
+			item: &gofeed.Item{Content: "<p>full body</p>"},
+			want: "<p>full body</p>",
+		},
+		{
+			name: "description wins over content when both set",
+			item: &gofeed.Item{Description: "<p>desc</p>", Content: "<p>full body</p>"},
+			want: "<p>desc</p>",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Extract(c.item).DescriptionHTML
+			if got != c.want {
+				t.Errorf("DescriptionHTML = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestExtractImageFallsBackToScrapedImage(t *testing.T) {
+	item := &gofeed.Item{
+		Description: `<p>hello <img src="https://example.com/scraped.png"></p>`,
+	}
+	e := Extract(item)
+	if e.Thumbnail != nil {
+		t.Fatalf("expected no structured thumbnail, got %+v", e.Thumbnail)
+	}
+	if e.Image == nil || e.Image.URL != "https://example.com/scraped.png" {
+		t.Fatalf("expected scraped image fallback, got %+v", e.Image)
+	}
+}
+
+func TestExtractFallsBackToMediaDescription(t *testing.T) {
+	item := &gofeed.Item{
+		Extensions: ext.Extensions{
+			"media": {
+				"description": {{Value: "<p>media body</p>"}},
+			},
+		},
+	}
+	e := Extract(item)
+	if e.DescriptionHTML != "<p>media body</p>" {
+		t.Fatalf("DescriptionHTML = %q, want media:description", e.DescriptionHTML)
+	}
+}
+
+func TestExtractFallsBackToMediaCredit(t *testing.T) {
+	item := &gofeed.Item{
+		Extensions: ext.Extensions{
+			"media": {
+				"credit": {{Value: "Jane Doe"}},
+			},
+		},
+	}
+	e := Extract(item)
+	if e.Author != "Jane Doe" {
+		t.Fatalf("Author = %q, want media:credit fallback", e.Author)
+	}
+}
+
+func TestExtractPrefersMediaThumbnailOverScraped(t *testing.T) {
+	item := &gofeed.Item{
+		Description: `<p><img src="https://example.com/scraped.png"></p>`,
+		Extensions: ext.Extensions{
+			"media": {
+				"thumbnail": {
+					{Attrs: map[string]string{"url": "https://example.com/media.png", "width": "800", "height": "600"}},
+				},
+			},
+		},
+	}
+	e := Extract(item)
+	if e.Thumbnail == nil || e.Thumbnail.URL != "https://example.com/media.png" {
+		t.Fatalf("expected media thumbnail to win, got %+v", e.Thumbnail)
+	}
+}
@@ -0,0 +1,138 @@
+package feed
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// FetchState is a feed URL's conditional-GET state, carried between fetch
+// cycles so the caller can persist it (e.g. in the feed's YAML state).
+type FetchState struct {
+	ETag         string    `yaml:"etag,omitempty"`
+	LastModified string    `yaml:"lastModified,omitempty"`
+	BodyHash     string    `yaml:"bodyHash,omitempty"`
+	NextRun      time.Time `yaml:"nextRun,omitempty"`
+}
+
+// FetchResult is the outcome of a single Fetcher.Fetch call.
+type FetchResult struct {
+	Body        []byte // nil when NotModified is true
+	NotModified bool
+	State       FetchState
+}
+
+// Fetcher retrieves feed bodies over HTTP using conditional GET (ETag /
+// Last-Modified) so unchanged feeds short-circuit on a 304 instead of being
+// re-parsed, and negotiates Brotli/gzip compression. Transport is pluggable
+// so tests can inject canned responses.
+type Fetcher struct {
+	Transport http.RoundTripper
+}
+
+// NewFetcher returns a Fetcher using http.DefaultTransport.
+func NewFetcher() *Fetcher {
+	return &Fetcher{Transport: http.DefaultTransport}
+}
+
+// Fetch retrieves url, sending If-None-Match / If-Modified-Since from
+// state and Accept-Encoding: br, gzip. A 304, or a body whose hash matches
+// state.BodyHash, is reported as NotModified. A 429/503 with Retry-After
+// pushes state.NextRun forward by that duration instead of erroring, so the
+// caller can reschedule the feed rather than hammering it.
+func (f *Fetcher) Fetch(ctx context.Context, url string, state FetchState) (*FetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	client := &http.Client{Transport: f.transport()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return &FetchResult{NotModified: true, State: state}, nil
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		state.NextRun = time.Now().Add(retryAfter(resp.Header.Get("Retry-After")))
+		return &FetchResult{NotModified: true, State: state}, nil
+	}
+
+	body, err := decodeBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashBody(body)
+	if hash == state.BodyHash {
+		return &FetchResult{NotModified: true, State: state}, nil
+	}
+
+	state.ETag = resp.Header.Get("ETag")
+	state.LastModified = resp.Header.Get("Last-Modified")
+	state.BodyHash = hash
+	state.NextRun = time.Time{}
+
+	return &FetchResult{Body: body, State: state}, nil
+}
+
+func (f *Fetcher) transport() http.RoundTripper {
+	if f.Transport != nil {
+		return f.Transport
+	}
+	return http.DefaultTransport
+}
+
+func decodeBody(resp *http.Response) ([]byte, error) {
+	var r io.Reader = resp.Body
+	switch resp.Header.Get("Content-Encoding") {
+	case "br":
+		r = brotli.NewReader(resp.Body)
+	case "gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return io.ReadAll(r)
+}
+
+func hashBody(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func retryAfter(header string) time.Duration {
+	const defaultBackoff = 60 * time.Second
+	if header == "" {
+		return defaultBackoff
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return defaultBackoff
+}
@@ -0,0 +1,137 @@
+package feed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newResponse(status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func TestFetchDecodesGzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte("<rss>gzip body</rss>"))
+	gz.Close()
+
+	fetcher := &Fetcher{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		h := http.Header{"Content-Encoding": {"gzip"}, "ETag": {`"v1"`}}
+		return newResponse(http.StatusOK, h, buf.Bytes()), nil
+	})}
+
+	result, err := fetcher.Fetch(context.Background(), "http://example.com/feed", FetchState{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(result.Body) != "<rss>gzip body</rss>" {
+		t.Fatalf("Body = %q, want decoded gzip body", result.Body)
+	}
+	if result.State.ETag != `"v1"` {
+		t.Fatalf("State.ETag = %q, want to be captured from response", result.State.ETag)
+	}
+}
+
+func TestFetchDecodesBrotli(t *testing.T) {
+	var buf bytes.Buffer
+	bw := brotli.NewWriter(&buf)
+	bw.Write([]byte("<rss>brotli body</rss>"))
+	bw.Close()
+
+	fetcher := &Fetcher{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		h := http.Header{"Content-Encoding": {"br"}}
+		return newResponse(http.StatusOK, h, buf.Bytes()), nil
+	})}
+
+	result, err := fetcher.Fetch(context.Background(), "http://example.com/feed", FetchState{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if string(result.Body) != "<rss>brotli body</rss>" {
+		t.Fatalf("Body = %q, want decoded brotli body", result.Body)
+	}
+}
+
+func TestFetchSendsConditionalHeadersAndHandles304(t *testing.T) {
+	var gotIfNoneMatch, gotIfModifiedSince string
+
+	fetcher := &Fetcher{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotIfNoneMatch = req.Header.Get("If-None-Match")
+		gotIfModifiedSince = req.Header.Get("If-Modified-Since")
+		return newResponse(http.StatusNotModified, nil, nil), nil
+	})}
+
+	state := FetchState{ETag: `"abc"`, LastModified: "Mon, 01 Jan 2024 00:00:00 GMT"}
+	result, err := fetcher.Fetch(context.Background(), "http://example.com/feed", state)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if gotIfNoneMatch != state.ETag {
+		t.Fatalf("If-None-Match = %q, want %q", gotIfNoneMatch, state.ETag)
+	}
+	if gotIfModifiedSince != state.LastModified {
+		t.Fatalf("If-Modified-Since = %q, want %q", gotIfModifiedSince, state.LastModified)
+	}
+	if !result.NotModified {
+		t.Fatal("expected NotModified on 304")
+	}
+	if result.State != state {
+		t.Fatalf("State = %+v, want unchanged %+v", result.State, state)
+	}
+}
+
+func TestFetchTreatsMatchingBodyHashAsNotModified(t *testing.T) {
+	body := []byte("<rss>same body</rss>")
+	state := FetchState{BodyHash: hashBody(body)}
+
+	fetcher := &Fetcher{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(http.StatusOK, nil, body), nil
+	})}
+
+	result, err := fetcher.Fetch(context.Background(), "http://example.com/feed", state)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !result.NotModified {
+		t.Fatal("expected NotModified when body hash is unchanged")
+	}
+}
+
+func TestFetchPushesNextRunOnRetryAfter(t *testing.T) {
+	fetcher := &Fetcher{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		h := http.Header{"Retry-After": {"30"}}
+		return newResponse(http.StatusTooManyRequests, h, nil), nil
+	})}
+
+	before := time.Now()
+	result, err := fetcher.Fetch(context.Background(), "http://example.com/feed", FetchState{})
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if !result.NotModified {
+		t.Fatal("expected NotModified on 429")
+	}
+	if d := result.State.NextRun.Sub(before); d < 29*time.Second || d > 31*time.Second {
+		t.Fatalf("NextRun pushed forward by %v, want ~30s", d)
+	}
+}
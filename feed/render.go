@@ -0,0 +1,148 @@
+package feed
+
+import (
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/tdewolff/minify/v2"
+	"github.com/tdewolff/minify/v2/html"
+)
+
+// SanitizePolicy selects which bluemonday policy is used to clean an item's
+// HTML description before it's rendered for Discord.
+type SanitizePolicy string
+
+const (
+	// PolicyStrict strips all markup, leaving plain text.
+	PolicyStrict SanitizePolicy = "strict"
+	// PolicyUGC allows the bluemonday user-generated-content tag set.
+	PolicyUGC SanitizePolicy = "ugc"
+	// PolicyMarkdown allows the handful of tags that have a clean Markdown
+	// equivalent (bold, italic, links, lists, code, blockquote).
+	PolicyMarkdown SanitizePolicy = "markdown"
+	// PolicyCustom allows exactly the tags listed in RenderOptions.AllowedTags.
+	PolicyCustom SanitizePolicy = "custom"
+)
+
+var markdownTags = []string{"b", "strong", "i", "em", "a", "ul", "ol", "li", "p", "br", "code", "pre", "blockquote"}
+
+// RenderOptions controls how Render turns an item's HTML description into
+// the text that gets sent to Discord.
+type RenderOptions struct {
+	Policy      SanitizePolicy
+	AllowedTags []string // only consulted when Policy == PolicyCustom
+	MaxChars    int
+	StripImages bool
+}
+
+func (o RenderOptions) bluemondayPolicy() *bluemonday.Policy {
+	switch o.Policy {
+	case PolicyUGC:
+		return bluemonday.UGCPolicy()
+	case PolicyMarkdown:
+		p := bluemonday.NewPolicy()
+		for _, t := range markdownTags {
+			p.AllowElements(t)
+		}
+		p.AllowAttrs("href").OnElements("a")
+		return p
+	case PolicyCustom:
+		p := bluemonday.NewPolicy()
+		for _, t := range o.AllowedTags {
+			p.AllowElements(t)
+		}
+		p.AllowAttrs("href").OnElements("a")
+		return p
+	default:
+		return bluemonday.StrictPolicy()
+	}
+}
+
+// Render sanitizes html per opts.Policy, minifies whitespace while it's
+// still real HTML, converts whatever markup survives to Markdown (so
+// Discord still renders bold/links/lists), and truncates to opts.MaxChars
+// on a rune boundary, preferring to cut at the end of a sentence within
+// that budget.
+func Render(html string, opts RenderOptions) string {
+	if opts.StripImages {
+		html = stripImages(html)
+	}
+
+	clean := opts.bluemondayPolicy().Sanitize(html)
+	clean = minifyWhitespace(clean)
+
+	converter := md.NewConverter("", true, nil)
+	rendered, err := converter.ConvertString(clean)
+	if err != nil {
+		rendered = clean
+	}
+
+	return truncate(rendered, opts.MaxChars)
+}
+
+func stripImages(htmlStr string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlStr))
+	if err != nil {
+		return htmlStr
+	}
+	doc.Find("img").Remove()
+	out, err := doc.Html()
+	if err != nil {
+		return htmlStr
+	}
+	return out
+}
+
+func minifyWhitespace(s string) string {
+	m := minify.New()
+	m.AddFunc("text/html", html.Minify)
+	out, err := m.String("text/html", s)
+	if err != nil {
+		return s
+	}
+	return out
+}
+
+const ellipsis = "..."
+
+// truncate cuts s to at most max runes, never splitting a multi-byte rune,
+// and prefers to stop at the end of a sentence within that budget. When it
+// falls back to a hard cut, the ellipsis itself is counted against max so
+// the result never exceeds the budget.
+func truncate(s string, max int) string {
+	if max <= 0 {
+		return s
+	}
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+
+	budget := string(runes[:max])
+	if end := lastSentenceEnd(budget); end > 0 {
+		return budget[:end]
+	}
+
+	cut := max - len([]rune(ellipsis))
+	if cut < 0 {
+		cut = 0
+	}
+	return strings.TrimRight(string(runes[:cut]), " \n\t") + ellipsis
+}
+
+// lastSentenceEnd returns the byte index just past the last sentence
+// terminator in s, or -1 if none is found.
+func lastSentenceEnd(s string) int {
+	best := -1
+	for _, sep := range []string{". ", "! ", "? ", "\n"} {
+		if idx := strings.LastIndex(s, sep); idx != -1 {
+			end := idx + len(sep) - 1
+			if end > best {
+				best = end
+			}
+		}
+	}
+	return best
+}
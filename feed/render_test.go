@@ -0,0 +1,38 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateRespectsBudgetWithoutSentenceBoundary(t *testing.T) {
+	// No ". ", "! ", "? " or "\n" inside the budget, so truncate must fall
+	// back to a hard cut and still fit the ellipsis inside max runes.
+	text := strings.Repeat("あ", 20)
+
+	got := Render(text, RenderOptions{MaxChars: 10})
+	if n := len([]rune(got)); n > 10 {
+		t.Fatalf("truncated result has %d runes, want <= 10 (%q)", n, got)
+	}
+	if !strings.HasSuffix(got, ellipsis) {
+		t.Fatalf("expected result to end with ellipsis, got %q", got)
+	}
+}
+
+func TestTruncatePrefersSentenceBoundary(t *testing.T) {
+	got := truncate("Hello world. Trailing text that should be dropped.", 20)
+	if got != "Hello world." {
+		t.Fatalf("got %q, want %q", got, "Hello world.")
+	}
+}
+
+func TestRenderDoesNotMangleLiteralAngleBracketText(t *testing.T) {
+	got := Render("<p>Visit &lt;https://example.com&gt; for more &amp; stuff</p>", RenderOptions{
+		Policy:   PolicyMarkdown,
+		MaxChars: 1000,
+	})
+	want := "Visit <https://example.com> for more & stuff"
+	if strings.TrimSpace(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
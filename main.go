@@ -4,18 +4,19 @@ import (
 	"context"
 	"flag"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"regexp"
 	"sort"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
-	"github.com/microcosm-cc/bluemonday"
 	"github.com/mmcdole/gofeed"
+	extractedfeed "github.com/roffe/rssbot/feed"
+	"github.com/roffe/rssbot/notify"
+	"github.com/roffe/rssbot/server"
 	"github.com/roffe/rssbot/webhook"
 	"gopkg.in/yaml.v2"
 )
@@ -24,6 +25,8 @@ var (
 	debug      = false
 	config     *RuntimeConfig
 	configFile string
+	dispatched *server.Store
+	fetcher    = extractedfeed.NewFetcher()
 )
 
 // used for semaphore chan
@@ -38,18 +41,32 @@ type RuntimeConfig struct {
 
 // Config is the app conf
 type Config struct {
-	MaxConcurrency int `yaml:"maxConcurrency"`
+	MaxConcurrency int           `yaml:"maxConcurrency"`
+	ServerAddr     string        `yaml:"serverAddr"`
+	FeedStorePath  string        `yaml:"feedStorePath"`
+	FeedCap        int           `yaml:"feedCap"`
+	FeedTTL        time.Duration `yaml:"feedTTL"`
 }
 
 // Feed config
 type Feed struct {
-	Hooks         []string      `yaml:"discordHooks"`
-	Color         string        `yaml:"color"`
-	LastPublished time.Time     `yaml:"lastPublished"`
-	LastRun       time.Time     `yaml:"lastRun"`
-	LastUpdated   time.Time     `yaml:"lastUpdate"`
-	Periode       time.Duration `yaml:"periode"`
-	URL           string        `yaml:"url"`
+	// Hooks is deprecated in favor of Sinks; for a feed with no sinks
+	// configured, loadConfig converts its discordHooks entries into
+	// discord-kind sinks on load. Hooks are ignored once sinks are set.
+	Hooks               []string                     `yaml:"discordHooks,omitempty"`
+	Sinks               []notify.Config              `yaml:"sinks"`
+	Color               string                       `yaml:"color"`
+	LastRun             time.Time                    `yaml:"lastRun"`
+	Periode             time.Duration                `yaml:"periode"`
+	URL                 string                       `yaml:"url"`
+	SanitizePolicy      extractedfeed.SanitizePolicy `yaml:"sanitizePolicy"`
+	AllowedTags         []string                     `yaml:"allowedTags"`
+	MaxDescriptionChars int                          `yaml:"maxDescriptionChars"`
+	StripImages         bool                         `yaml:"stripImages"`
+	FetchState          extractedfeed.FetchState     `yaml:"fetchState"`
+	Seen                extractedfeed.SeenSet        `yaml:"seen"`
+
+	sinks []notify.Sink
 }
 
 func loadConfig() {
@@ -63,6 +80,14 @@ func loadConfig() {
 	if err := d.Decode(&config); err != nil {
 		log.Fatal(err)
 	}
+
+	for _, feed := range config.Feeds {
+		if len(feed.Sinks) == 0 {
+			for _, hookURL := range feed.Hooks {
+				feed.Sinks = append(feed.Sinks, notify.Config{Kind: notify.KindDiscord, URL: hookURL})
+			}
+		}
+	}
 }
 
 func saveConfig() {
@@ -92,16 +117,40 @@ func init() {
 	flag.Parse()
 	webhook.Debug = debug
 	loadConfig()
+	if config.Config.ServerAddr == "" {
+		config.Config.ServerAddr = ":8080"
+	}
+	if config.Config.FeedStorePath == "" {
+		config.Config.FeedStorePath = "dispatched.json"
+	}
+	if config.Config.FeedCap == 0 {
+		config.Config.FeedCap = 200
+	}
 	log.Println("RSS Scraper starting")
 	log.Println("maxConcurrency:", config.Config.MaxConcurrency)
 	if debug {
 		log.Println("debug mode enabled")
 	}
+
+	var err error
+	dispatched, err = server.NewStore(config.Config.FeedStorePath, config.Config.FeedCap, config.Config.FeedTTL)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
 func main() {
 	defer saveConfig()
 
+	feedServer := server.New(config.Config.ServerAddr, dispatched)
+	go func() {
+		log.Println("serving dispatch feed on", config.Config.ServerAddr)
+		if err := feedServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Println(err)
+		}
+	}()
+	defer feedServer.Shutdown()
+
 	save := time.NewTicker(10 * time.Second)
 	sigChan := make(chan os.Signal)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -146,6 +195,9 @@ func runFeeds(ctx context.Context) {
 			//fmt.Println("Skipping, Time since last run ", time.Since(feed.LastRun))
 			continue
 		}
+		if time.Now().Before(feed.FetchState.NextRun) {
+			continue
+		}
 		sem <- token{}
 		wg.Add(1)
 		go processFeed(ctx, name, feed, sem, &wg)
@@ -155,6 +207,56 @@ func runFeeds(ctx context.Context) {
 
 var nl = regexp.MustCompile(`\n{3,}`)
 
+func thumbnailURL(item *extractedfeed.ExtractedItem) string {
+	if item.Thumbnail != nil {
+		return item.Thumbnail.URL
+	}
+	if item.Image != nil {
+		return item.Image.URL
+	}
+	return ""
+}
+
+// publishedTime picks the best available timestamp for news, since Atom
+// feeds that only set <updated> (and no <published>) leave PublishedParsed
+// nil.
+func publishedTime(news *gofeed.Item) time.Time {
+	if news.PublishedParsed != nil {
+		return *news.PublishedParsed
+	}
+	if news.UpdatedParsed != nil {
+		return *news.UpdatedParsed
+	}
+	return time.Now()
+}
+
+// sinksFor resolves and caches feed's configured notify.Sinks.
+func sinksFor(feed *Feed) []notify.Sink {
+	if feed.sinks == nil {
+		for _, cfg := range feed.Sinks {
+			sink, err := notify.New(cfg)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			feed.sinks = append(feed.sinks, sink)
+		}
+	}
+	return feed.sinks
+}
+
+func sendToSink(ctx context.Context, sink notify.Sink, items []*notify.Item) error {
+	if batch, ok := sink.(notify.BatchSink); ok {
+		return batch.SendBatch(ctx, items)
+	}
+	for _, item := range items {
+		if err := sink.Send(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func processFeed(ctx context.Context, name string, feed *Feed, sem chan token, wg *sync.WaitGroup) {
 	defer func() {
 		<-sem
@@ -168,80 +270,80 @@ func processFeed(ctx context.Context, name string, feed *Feed, sem chan token, w
 	ctx2, cancel := context.WithTimeout(ctx, 8*time.Second)
 	defer cancel()
 
-	rssFeed, err := gofeed.NewParser().ParseURLWithContext(feed.URL, ctx2)
+	result, err := fetcher.Fetch(ctx2, feed.URL, feed.FetchState)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	feed.FetchState = result.State
+	if result.NotModified {
+		if debug {
+			log.Printf("%s not modified, skipping\n", name)
+		}
+		feed.LastRun = time.Now()
+		return
+	}
+
+	rssFeed, err := gofeed.NewParser().ParseString(string(result.Body))
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	p := bluemonday.StrictPolicy()
 	sort.Sort(rssFeed)
-	if feed.LastUpdated.Unix() < rssFeed.UpdatedParsed.Unix() {
-		for _, news := range rssFeed.Items {
-			if feed.LastPublished.Unix() < news.PublishedParsed.Unix() {
-				feed.LastPublished = *news.PublishedParsed
-				log.Println(news.Title, news.Published)
-				r := strings.NewReader(news.Description)
-				doc, err := goquery.NewDocumentFromReader(r)
-				if err != nil {
-					log.Println(err)
-					continue
-				}
-				var image string
-				doc.Find("img").EachWithBreak(func(i int, s *goquery.Selection) bool {
-					img, exists := s.Attr("src")
-					if exists {
-						image = img
-					}
-					return false
-				})
-
-				desc := p.Sanitize(news.Description)
-				desc = strings.ReplaceAll(desc, "\t", "")
-				desc = nl.ReplaceAllString(desc, "\n")
-				cap := 500
-				if len(desc) < 500 {
-					cap = len(desc)
-				}
-				for _, hookURL := range feed.Hooks {
-					msg := webhook.NewMessage(hookURL, true)
-					e := &webhook.Embed{
-						Title:       news.Title,
-						Type:        webhook.TypeRich,
-						Description: desc[0:cap] + "...",
-						URL:         news.Link,
-						Color:       webhook.Hex2int(feed.Color),
-						Timestamp:   news.PublishedParsed,
-						Thumbnail: &webhook.EmbedThumbnail{
-							URL:    "https://static.mmo-champion.com/images/tranquilizing/logo.png",
-							Width:  157,
-							Height: 90,
-						},
-						Author: &webhook.EmbedAuthor{
-							Name:    "By Purple Haze",
-							URL:     "https://purplehazeeu.com",
-							IconURL: "https://purplehazeeu.com/wp/wp-content/uploads/2020/09/ph-logo-smal.png",
-						},
-					}
-					if image != "" {
-						e.Image = &webhook.EmbedImage{
-							URL: image,
-						}
-					}
 
-					if news.Image != nil {
-						e.Thumbnail = &webhook.EmbedThumbnail{
-							URL: news.Image.URL,
-						}
-					}
-					msg.AddEmbed(e)
-					if err := msg.Send(); err != nil {
-						log.Print(err)
-					}
-					time.Sleep(100 * time.Millisecond)
-				}
+	var items []*notify.Item
+	for _, news := range rssFeed.Items {
+		item := extractedfeed.Extract(news)
+		key := extractedfeed.ItemKey(item, item.DescriptionHTML)
+		if feed.Seen.Seen(key) {
+			continue
+		}
+		feed.Seen.Add(key)
+		log.Println(item.Title, news.Published)
+
+		maxChars := feed.MaxDescriptionChars
+		if maxChars == 0 {
+			maxChars = 500
+		}
+		desc := extractedfeed.Render(item.DescriptionHTML, extractedfeed.RenderOptions{
+			Policy:      feed.SanitizePolicy,
+			AllowedTags: feed.AllowedTags,
+			MaxChars:    maxChars,
+			StripImages: feed.StripImages,
+		})
+		desc = nl.ReplaceAllString(desc, "\n")
+		published := publishedTime(news)
+
+		items = append(items, &notify.Item{
+			Title:     item.Title,
+			Link:      item.Link,
+			Summary:   desc,
+			Image:     thumbnailURL(item),
+			Author:    item.Author,
+			Color:     webhook.Hex2int(feed.Color),
+			Timestamp: published,
+		})
+
+		if err := dispatched.Add(server.Item{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: desc,
+			Thumbnail:   thumbnailURL(item),
+			SourceFeed:  name,
+			Published:   published,
+		}); err != nil {
+			log.Println("recording dispatched item:", err)
+		}
+	}
+
+	if len(items) > 0 {
+		dispatchCtx, dispatchCancel := context.WithTimeout(ctx, 30*time.Second)
+		for _, sink := range sinksFor(feed) {
+			if err := sendToSink(dispatchCtx, sink, items); err != nil {
+				log.Print(err)
 			}
 		}
-		feed.LastUpdated = *rssFeed.UpdatedParsed
+		dispatchCancel()
 	}
 	/*
 		if feed.LastUpdated.Unix() < rssFeed.UpdatedParsed.Unix() {
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/roffe/rssbot/webhook"
+)
+
+// discordClient is shared by every DiscordSink so they all draw on one
+// worker pool and their rate limit buckets are tracked per Discord webhook
+// URL, not per feed.
+var discordClient = webhook.NewClient(8)
+
+// DiscordSink posts items as Discord embeds.
+type DiscordSink struct {
+	url string
+}
+
+// NewDiscordSink returns a Sink that posts to a Discord incoming webhook.
+func NewDiscordSink(url string) *DiscordSink {
+	return &DiscordSink{url: url}
+}
+
+// Send implements Sink.
+func (d *DiscordSink) Send(ctx context.Context, item *Item) error {
+	return d.SendBatch(ctx, []*Item{item})
+}
+
+// SendBatch implements BatchSink, coalescing items into as few messages as
+// Discord's ten-embeds-per-message limit allows.
+func (d *DiscordSink) SendBatch(ctx context.Context, items []*Item) error {
+	embeds := make([]*webhook.Embed, 0, len(items))
+	for _, it := range items {
+		embeds = append(embeds, discordEmbed(it))
+	}
+	return discordClient.SendEmbeds(ctx, d.url, embeds)
+}
+
+func discordEmbed(it *Item) *webhook.Embed {
+	e := &webhook.Embed{
+		Title:       it.Title,
+		Type:        webhook.TypeRich,
+		Description: it.Summary,
+		URL:         it.Link,
+		Color:       it.Color,
+	}
+	if !it.Timestamp.IsZero() {
+		ts := it.Timestamp
+		e.Timestamp = &ts
+	}
+	if it.Image != "" {
+		e.Image = &webhook.EmbedImage{URL: it.Image}
+	}
+	if it.Author != "" {
+		e.Author = &webhook.EmbedAuthor{Name: it.Author}
+	}
+	for _, f := range it.Fields {
+		e.AddField(&webhook.EmbedField{Name: f.Name, Value: f.Value, Inline: f.Inline})
+	}
+	return e
+}
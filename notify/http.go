@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+)
+
+var httpClient = &http.Client{}
+
+func postJSON(ctx context.Context, url string, body interface{}) error {
+	return sendJSON(ctx, http.MethodPost, url, body)
+}
+
+func sendJSON(ctx context.Context, method, url string, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return sendBody(ctx, method, url, "application/json", bytes.NewReader(b))
+}
+
+func sendBody(ctx context.Context, method, url, contentType string, body *bytes.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("notify: %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+const defaultHTTPTemplate = `{"title":{{.Title | printf "%q"}},"link":{{.Link | printf "%q"}},"summary":{{.Summary | printf "%q"}}}`
+
+// HTTPSink POSTs a Go-templated JSON body to an arbitrary URL, for
+// integrations that don't have a dedicated Sink.
+type HTTPSink struct {
+	url  string
+	tmpl *template.Template
+}
+
+// NewHTTPSink builds an HTTPSink that renders tmplSrc with an Item as data
+// for every dispatched entry. An empty tmplSrc falls back to a small
+// default JSON body.
+func NewHTTPSink(url, tmplSrc string) (*HTTPSink, error) {
+	if tmplSrc == "" {
+		tmplSrc = defaultHTTPTemplate
+	}
+	tmpl, err := template.New("sink").Parse(tmplSrc)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPSink{url: url, tmpl: tmpl}, nil
+}
+
+// Send implements Sink.
+func (h *HTTPSink) Send(ctx context.Context, item *Item) error {
+	var buf bytes.Buffer
+	if err := h.tmpl.Execute(&buf, item); err != nil {
+		return err
+	}
+	return sendBody(ctx, http.MethodPost, h.url, "application/json", bytes.NewReader(buf.Bytes()))
+}
+
+// AppriseSink bridges items to an Apprise HTTP API endpoint
+// (https://github.com/caronc/apprise-api), posting {title, body}.
+type AppriseSink struct {
+	url string
+}
+
+// NewAppriseSink returns a Sink that posts to an Apprise HTTP bridge.
+func NewAppriseSink(url string) *AppriseSink {
+	return &AppriseSink{url: url}
+}
+
+// Send implements Sink.
+func (a *AppriseSink) Send(ctx context.Context, item *Item) error {
+	return postJSON(ctx, a.url, map[string]string{
+		"title": item.Title,
+		"body":  item.Summary + "\n" + item.Link,
+	})
+}
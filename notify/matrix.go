@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// MatrixSink sends items as m.room.message events to a Matrix room. url is
+// expected to be the full
+// ".../_matrix/client/r0/rooms/{roomId}/send/m.room.message" endpoint,
+// including an access_token query parameter; a transaction id is appended
+// per send.
+type MatrixSink struct {
+	url string
+}
+
+// NewMatrixSink returns a Sink that PUTs m.room.message events to url.
+func NewMatrixSink(url string) *MatrixSink {
+	return &MatrixSink{url: url}
+}
+
+var matrixTxnCounter int64
+
+// txnURL appends txn as a path segment ahead of any query string (e.g. the
+// access_token parameter), rather than naively concatenating it onto url.
+func (m *MatrixSink) txnURL(txn string) (string, error) {
+	u, err := url.Parse(m.url)
+	if err != nil {
+		return "", err
+	}
+	u.Path = u.Path + "/" + txn
+	return u.String(), nil
+}
+
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// Send implements Sink.
+func (m *MatrixSink) Send(ctx context.Context, item *Item) error {
+	body := item.Title
+	if item.Link != "" {
+		body += " " + item.Link
+	}
+	msg := matrixMessage{
+		MsgType:       "m.text",
+		Body:          body,
+		Format:        "org.matrix.custom.html",
+		FormattedBody: fmt.Sprintf(`<b>%s</b><br>%s`, item.Title, item.Summary),
+	}
+
+	txn := strconv.FormatInt(atomic.AddInt64(&matrixTxnCounter, 1), 10) + strconv.FormatInt(time.Now().UnixNano(), 36)
+	sendURL, err := m.txnURL(txn)
+	if err != nil {
+		return err
+	}
+	return sendJSON(ctx, http.MethodPut, sendURL, msg)
+}
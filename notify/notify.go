@@ -0,0 +1,79 @@
+// Package notify decouples feed dispatch from any one chat backend. A Sink
+// takes the sink-agnostic Item produced from a feed entry and translates it
+// into whatever envelope its backend expects.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Field is a labeled value attached to an Item, e.g. a Discord embed field.
+type Field struct {
+	Name   string
+	Value  string
+	Inline bool
+}
+
+// Item is the sink-agnostic content of a single feed entry to be dispatched.
+type Item struct {
+	Title     string
+	Link      string
+	Summary   string
+	Image     string
+	Author    string
+	Color     int
+	Timestamp time.Time
+	Fields    []Field
+}
+
+// Sink delivers a single Item to a notification backend.
+type Sink interface {
+	Send(ctx context.Context, item *Item) error
+}
+
+// BatchSink is implemented by sinks that can coalesce the items from one
+// feed run into fewer outbound requests, e.g. Discord's embeds-per-message
+// limit.
+type BatchSink interface {
+	SendBatch(ctx context.Context, items []*Item) error
+}
+
+// Sink kinds recognized by New.
+const (
+	KindDiscord = "discord"
+	KindSlack   = "slack"
+	KindTeams   = "teams"
+	KindMatrix  = "matrix"
+	KindHTTP    = "http"
+	KindApprise = "apprise"
+)
+
+// Config describes a single configured sink.
+type Config struct {
+	Kind     string `yaml:"kind"`
+	URL      string `yaml:"url"`
+	Template string `yaml:"template,omitempty"` // only used by KindHTTP
+}
+
+// New builds the Sink described by cfg. An empty Kind defaults to Discord so
+// existing configs that only ever spoke Discord keep working.
+func New(cfg Config) (Sink, error) {
+	switch cfg.Kind {
+	case KindDiscord, "":
+		return NewDiscordSink(cfg.URL), nil
+	case KindSlack:
+		return NewSlackSink(cfg.URL), nil
+	case KindTeams:
+		return NewTeamsSink(cfg.URL), nil
+	case KindMatrix:
+		return NewMatrixSink(cfg.URL), nil
+	case KindHTTP:
+		return NewHTTPSink(cfg.URL, cfg.Template)
+	case KindApprise:
+		return NewAppriseSink(cfg.URL), nil
+	default:
+		return nil, fmt.Errorf("notify: unknown sink kind %q", cfg.Kind)
+	}
+}
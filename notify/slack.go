@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+)
+
+// SlackSink posts items to a Slack incoming webhook as a message attachment.
+type SlackSink struct {
+	url string
+}
+
+// NewSlackSink returns a Sink that posts to a Slack incoming webhook.
+func NewSlackSink(url string) *SlackSink {
+	return &SlackSink{url: url}
+}
+
+type slackPayload struct {
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Title      string `json:"title,omitempty"`
+	TitleLink  string `json:"title_link,omitempty"`
+	Text       string `json:"text,omitempty"`
+	ImageURL   string `json:"image_url,omitempty"`
+	AuthorName string `json:"author_name,omitempty"`
+	Color      string `json:"color,omitempty"`
+	Ts         int64  `json:"ts,omitempty"`
+}
+
+// Send implements Sink.
+func (s *SlackSink) Send(ctx context.Context, item *Item) error {
+	att := slackAttachment{
+		Title:      item.Title,
+		TitleLink:  item.Link,
+		Text:       item.Summary,
+		ImageURL:   item.Image,
+		AuthorName: item.Author,
+		Color:      hexColor(item.Color),
+	}
+	if !item.Timestamp.IsZero() {
+		att.Ts = item.Timestamp.Unix()
+	}
+	return postJSON(ctx, s.url, slackPayload{Attachments: []slackAttachment{att}})
+}
+
+func hexColor(c int) string {
+	if c == 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%06x", c)
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"context"
+)
+
+// TeamsSink posts items to a Microsoft Teams incoming webhook as a
+// MessageCard.
+type TeamsSink struct {
+	url string
+}
+
+// NewTeamsSink returns a Sink that posts to a Teams incoming webhook.
+func NewTeamsSink(url string) *TeamsSink {
+	return &TeamsSink{url: url}
+}
+
+type teamsMessageCard struct {
+	Type            string               `json:"@type"`
+	Context         string               `json:"@context"`
+	Summary         string               `json:"summary"`
+	ThemeColor      string               `json:"themeColor,omitempty"`
+	Title           string               `json:"title,omitempty"`
+	Text            string               `json:"text,omitempty"`
+	PotentialAction []teamsOpenURIAction `json:"potentialAction,omitempty"`
+}
+
+type teamsOpenURIAction struct {
+	Type    string           `json:"@type"`
+	Name    string           `json:"name"`
+	Targets []teamsURITarget `json:"targets"`
+}
+
+type teamsURITarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// Send implements Sink.
+func (t *TeamsSink) Send(ctx context.Context, item *Item) error {
+	card := teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		Summary:    item.Title,
+		ThemeColor: hexColor(item.Color),
+		Title:      item.Title,
+		Text:       item.Summary,
+	}
+	if item.Link != "" {
+		card.PotentialAction = []teamsOpenURIAction{{
+			Type: "OpenUri",
+			Name: "Read more",
+			Targets: []teamsURITarget{{
+				OS:  "default",
+				URI: item.Link,
+			}},
+		}}
+	}
+	return postJSON(ctx, t.url, card)
+}
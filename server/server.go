@@ -0,0 +1,106 @@
+// Package server publishes the bot's own dispatch history as RSS 2.0 and
+// Atom 1.0 feeds, combined and per-source-feed, turning rssbot into a
+// bidirectional relay: RSS in, notifications out, and RSS out for
+// downstream tooling.
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/feeds"
+)
+
+// Server serves the feeds built from a Store.
+type Server struct {
+	store *Store
+	http  *http.Server
+}
+
+// New creates a Server that listens on addr and publishes items from store.
+func New(addr string, store *Store) *Server {
+	s := &Server{store: store}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feeds/all.rss", s.handleRSS(""))
+	mux.HandleFunc("/feeds/all.atom", s.handleAtom(""))
+	mux.HandleFunc("/feeds/", s.handleSource)
+	s.http = &http.Server{Addr: addr, Handler: mux}
+	return s
+}
+
+// ListenAndServe starts the HTTP server, blocking until it stops.
+func (s *Server) ListenAndServe() error {
+	return s.http.ListenAndServe()
+}
+
+// Shutdown closes the HTTP server immediately, dropping any active
+// connections.
+func (s *Server) Shutdown() error {
+	return s.http.Close()
+}
+
+// handleSource serves /feeds/<name>.rss and /feeds/<name>.atom for a single
+// source feed.
+func (s *Server) handleSource(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/feeds/")
+	switch {
+	case strings.HasSuffix(name, ".rss"):
+		s.handleRSS(strings.TrimSuffix(name, ".rss"))(w, r)
+	case strings.HasSuffix(name, ".atom"):
+		s.handleAtom(strings.TrimSuffix(name, ".atom"))(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleRSS(source string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rss, err := s.buildFeed(source).ToRss()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		w.Write([]byte(rss))
+	}
+}
+
+func (s *Server) handleAtom(source string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atom, err := s.buildFeed(source).ToAtom()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		w.Write([]byte(atom))
+	}
+}
+
+func (s *Server) buildFeed(source string) *feeds.Feed {
+	title := "rssbot dispatch history"
+	items := s.store.All()
+	if source != "" {
+		title = "rssbot dispatch history: " + source
+		items = s.store.BySource(source)
+	}
+
+	f := &feeds.Feed{
+		Title:       title,
+		Description: "Items this bot has dispatched to its sinks",
+	}
+
+	for _, it := range items {
+		fi := &feeds.Item{
+			Title:       it.Title,
+			Link:        &feeds.Link{Href: it.Link},
+			Description: it.Description,
+			Created:     it.Published,
+		}
+		if it.Thumbnail != "" {
+			fi.Enclosure = &feeds.Enclosure{Url: it.Thumbnail, Type: "image"}
+		}
+		f.Items = append(f.Items, fi)
+	}
+	return f
+}
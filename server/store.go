@@ -0,0 +1,131 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Item is a single entry the bot has dispatched to its feed's sinks, kept
+// around so it can be republished as RSS/Atom.
+type Item struct {
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	Description string    `json:"description"`
+	Thumbnail   string    `json:"thumbnail,omitempty"`
+	SourceFeed  string    `json:"sourceFeed"`
+	Published   time.Time `json:"published"`
+}
+
+// Store persists the most recently dispatched items to disk so restarts
+// don't lose history. It keeps at most Cap items and drops anything older
+// than TTL (when TTL is non-zero).
+type Store struct {
+	path string
+	cap  int
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	items []Item
+}
+
+// NewStore opens (or creates) the on-disk store at path, capped at cap items
+// and ttl age. A zero ttl disables age-based eviction. A missing or
+// corrupt (e.g. truncated by a crash mid-write) store file is treated as an
+// empty history rather than a fatal error, so a bad file on disk never
+// prevents the bot from starting.
+func NewStore(path string, cap int, ttl time.Duration) (*Store, error) {
+	s := &Store{path: path, cap: cap, ttl: ttl}
+	if err := s.load(); err != nil && !os.IsNotExist(err) {
+		log.Printf("server: discarding unreadable store %s: %v", path, err)
+		s.items = nil
+	}
+	return s, nil
+}
+
+func (s *Store) load() error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return json.NewDecoder(f).Decode(&s.items)
+}
+
+// save writes items to a temp file in the same directory and renames it
+// over path, so a crash or power loss mid-write can't leave a truncated or
+// empty store file behind.
+func (s *Store) save() error {
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if err := json.NewEncoder(tmp).Encode(s.items); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+// Add records an item and persists the store, pruning anything past the
+// configured cap or TTL.
+func (s *Store) Add(item Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items = append(s.items, item)
+	s.prune()
+	return s.save()
+}
+
+func (s *Store) prune() {
+	if s.ttl > 0 {
+		cutoff := time.Now().Add(-s.ttl)
+		kept := s.items[:0]
+		for _, it := range s.items {
+			if it.Published.After(cutoff) {
+				kept = append(kept, it)
+			}
+		}
+		s.items = kept
+	}
+	sort.Slice(s.items, func(i, j int) bool {
+		return s.items[i].Published.After(s.items[j].Published)
+	})
+	if s.cap > 0 && len(s.items) > s.cap {
+		s.items = s.items[:s.cap]
+	}
+}
+
+// All returns the stored items, newest first.
+func (s *Store) All() []Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Item, len(s.items))
+	copy(out, s.items)
+	return out
+}
+
+// BySource returns the stored items for a single source feed, newest first.
+func (s *Store) BySource(name string) []Item {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Item
+	for _, it := range s.items {
+		if it.SourceFeed == name {
+			out = append(out, it)
+		}
+	}
+	return out
+}
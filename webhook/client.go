@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxEmbedsPerMessage is Discord's limit on embeds per webhook message.
+const maxEmbedsPerMessage = 10
+
+type workerToken struct{}
+
+// Client dispatches Messages to Discord webhooks through a bounded worker
+// pool shared across feeds. It tracks per-webhook-URL rate limit state from
+// the X-RateLimit-Remaining / X-RateLimit-Reset-After headers and retries
+// 429/5xx responses with exponential backoff, honoring Retry-After.
+type Client struct {
+	HTTPClient *http.Client
+
+	sem     chan workerToken
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewClient returns a Client that dispatches through at most workers
+// concurrent requests.
+func NewClient(workers int) *Client {
+	if workers <= 0 {
+		workers = 4
+	}
+	return &Client{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		sem:        make(chan workerToken, workers),
+		buckets:    make(map[string]*bucket),
+	}
+}
+
+// SendCtx sends msg, blocking until a worker slot frees up, the message is
+// delivered (after any rate-limit wait and retries), or ctx is done.
+func (c *Client) SendCtx(ctx context.Context, msg *Message) error {
+	if err := msg.IsValid(); err != nil {
+		return err
+	}
+
+	select {
+	case c.sem <- workerToken{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-c.sem }()
+
+	return c.send(ctx, msg)
+}
+
+// SendEmbeds sends embeds to url, splitting them into batches of at most
+// maxEmbedsPerMessage so a feed run with many new items still coalesces
+// down to as few messages as Discord allows.
+func (c *Client) SendEmbeds(ctx context.Context, url string, embeds []*Embed) error {
+	for i := 0; i < len(embeds); i += maxEmbedsPerMessage {
+		end := i + maxEmbedsPerMessage
+		if end > len(embeds) {
+			end = len(embeds)
+		}
+		msg := NewMessage(url, true)
+		msg.Embeds = embeds[i:end]
+		if err := c.SendCtx(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, msg *Message) error {
+	const maxAttempts = 5
+	backoff := 500 * time.Millisecond
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if wait := c.bucketFor(msg.url).waitDuration(); wait > 0 {
+			if err := sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+
+		resp, err := c.do(ctx, msg)
+		if err != nil {
+			return err
+		}
+
+		retry, wait := c.handleResponse(msg.url, resp)
+		if !retry {
+			return nil
+		}
+		if wait <= 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+	return errors.New("webhook: giving up after too many retries")
+}
+
+func (c *Client) do(ctx context.Context, msg *Message) (*http.Response, error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	if Debug {
+		log.Println(string(b))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.url, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.HTTPClient.Do(req)
+}
+
+// handleResponse updates the rate-limit bucket for url from resp's headers,
+// drains and closes the body, and reports whether the caller should retry
+// and if so after how long.
+func (c *Client) handleResponse(url string, resp *http.Response) (retry bool, wait time.Duration) {
+	defer resp.Body.Close()
+	c.bucketFor(url).update(resp.Header)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil && err != io.EOF {
+		log.Println(err)
+	}
+	if Debug {
+		log.Println(string(body))
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true, retryAfterDuration(resp.Header.Get("Retry-After"))
+	case resp.StatusCode >= 500:
+		return true, 0
+	default:
+		return false, 0
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// bucket tracks a single webhook URL's rate limit state.
+type bucket struct {
+	mu        sync.Mutex
+	remaining int
+	resetAt   time.Time
+}
+
+func (c *Client) bucketFor(url string) *bucket {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.buckets[url]
+	if !ok {
+		b = &bucket{remaining: 1}
+		c.buckets[url] = b
+	}
+	return b
+}
+
+func (b *bucket) update(h http.Header) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if v := h.Get("X-RateLimit-Remaining"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			b.remaining = n
+		}
+	}
+	if v := h.Get("X-RateLimit-Reset-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil {
+			b.resetAt = time.Now().Add(time.Duration(secs * float64(time.Second)))
+		}
+	}
+}
+
+func (b *bucket) waitDuration() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.remaining > 0 {
+		return 0
+	}
+	return time.Until(b.resetAt)
+}
+
+func retryAfterDuration(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(header, 64); err == nil {
+		return time.Duration(secs * float64(time.Second))
+	}
+	return 0
+}
@@ -1,13 +1,7 @@
 package webhook
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
-	"io"
-	"io/ioutil"
-	"log"
-	"net/http"
 	"net/url"
 	"strconv"
 	"time"
@@ -50,44 +44,6 @@ type Response struct {
 	RetryAfter int    `json:"retry_after"`
 }
 
-// Send the webhook meesage
-func (w *Message) Send() error {
-	if err := w.IsValid(); err != nil {
-		return err
-	}
-	b, err := json.Marshal(w)
-	if err != nil {
-		return err
-	}
-
-	if Debug {
-		log.Println(string(b[:]))
-	}
-
-	r := bytes.NewReader(b)
-	resp, err := http.Post(w.url, "application/json", r)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	var dresp Response
-	if err := json.NewDecoder(resp.Body).Decode(&dresp); err != nil {
-		if err != io.EOF {
-			log.Println(err)
-		}
-	}
-
-	if Debug {
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
-		log.Println(string(body[:]))
-	}
-	return nil
-}
-
 // IsValid validates so the message is correct
 func (w *Message) IsValid() error {
 	// one of content, file, embeds